@@ -0,0 +1,74 @@
+package gdbm
+
+// batchOp is a single buffered write or delete waiting to be applied to a
+// Database.
+type batchOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers Set/Delete calls and applies them to a Database in a single
+// critical section, amortizing the cost of locking dbf across many writes.
+type batch struct {
+	db  *Database
+	ops []batchOp
+}
+
+// NewBatch returns a Batch that buffers writes against d until Write or
+// WriteSync is called, at which point they are applied in order under a
+// single lock acquisition, so other users of d never see a partly applied
+// batch. If an operation fails, Write returns its error and the operations
+// before it remain applied; they are not rolled back.
+func (d *Database) NewBatch() Batch {
+	return &batch{db: d}
+}
+
+// Set buffers a write of value under key.
+func (b *batch) Set(key, value []byte) {
+	b.ops = append(b.ops, batchOp{key: key, value: value})
+}
+
+// Delete buffers a deletion of key.
+func (b *batch) Delete(key []byte) {
+	b.ops = append(b.ops, batchOp{key: key, delete: true})
+}
+
+// Write applies the buffered operations to the database under a single lock
+// acquisition, amortizing the cost of locking dbf across every buffered
+// write.
+func (b *batch) Write() error {
+	return b.apply()
+}
+
+// WriteSync behaves like Write, additionally blocking until the result has
+// been physically written to disk.
+func (b *batch) WriteSync() error {
+	if err := b.apply(); err != nil {
+		return err
+	}
+	b.db.Sync()
+	return nil
+}
+
+func (b *batch) apply() error {
+	d := b.db
+	d.lock()
+	defer d.unlock()
+
+	for _, op := range b.ops {
+		value := op.value
+		if op.delete {
+			value = nil
+		}
+		if err := d.storeLocked(op.key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close discards the batch. It is safe to call after Write or WriteSync.
+func (b *batch) Close() {
+	b.ops = nil
+}
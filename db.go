@@ -0,0 +1,101 @@
+package gdbm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNilValue is returned by DB.Set implementations when asked to store a
+// nil value, which gdbm and every other backend here treat as meaningless
+// (use Delete instead).
+var errNilValue = errors.New("gdbm: Set requires a non-nil value")
+
+// BackendType identifies a DB implementation that has been registered with
+// RegisterBackend.
+type BackendType string
+
+const (
+	// GDBMBackend stores data on disk using the GNU dbm library.
+	GDBMBackend BackendType = "gdbm"
+	// MemDBBackend keeps all data in memory. It is not persisted to disk
+	// and is mainly useful for tests.
+	MemDBBackend BackendType = "memdb"
+)
+
+// DB is a key/value store. The various backends registered with
+// RegisterBackend all implement this interface, so callers can depend on DB
+// instead of a specific backend and switch backends without touching call
+// sites.
+type DB interface {
+	// Get returns the value associated with key, or nil if key is not
+	// present in the database.
+	Get(key []byte) ([]byte, error)
+	// Has reports whether key is present in the database.
+	Has(key []byte) (bool, error)
+	// Set stores value under key, replacing any existing value for key.
+	// value must not be nil.
+	Set(key, value []byte) error
+	// Delete removes key from the database. It is not an error if key is
+	// not present.
+	Delete(key []byte) error
+	// Iterator returns an iterator over the key/value pairs of the
+	// database. The order of iteration is backend-specific.
+	Iterator() (Iterator, error)
+	// NewBatch returns a Batch that buffers writes for a single, amortized
+	// commit.
+	NewBatch() Batch
+	// Close releases all resources held by the database.
+	Close() error
+}
+
+// Iterator iterates over the key/value pairs of a DB. A newly created
+// Iterator is positioned before the first pair; call Next to advance it.
+type Iterator interface {
+	// Next advances the iterator to the next key/value pair. It returns
+	// false once iteration is complete.
+	Next() bool
+	// Key returns the key at the iterator's current position.
+	Key() []byte
+	// Value returns the value at the iterator's current position.
+	Value() []byte
+	// Close releases resources held by the iterator.
+	Close() error
+}
+
+// Batch buffers a sequence of writes so they can be committed together,
+// amortizing the cost of locking and syncing the underlying database.
+type Batch interface {
+	// Set buffers a write of value under key.
+	Set(key, value []byte)
+	// Delete buffers a deletion of key.
+	Delete(key []byte)
+	// Write commits the buffered operations to the database.
+	Write() error
+	// WriteSync commits the buffered operations and blocks until they are
+	// physically written to disk.
+	WriteSync() error
+	// Close discards the batch. It is safe to call after Write or
+	// WriteSync.
+	Close()
+}
+
+type backendCtor func(name, dir string) (DB, error)
+
+var backends = map[BackendType]backendCtor{}
+
+// RegisterBackend makes a DB implementation available under name for use
+// with NewDB. It is intended to be called from an init function.
+func RegisterBackend(name BackendType, ctor func(name, dir string) (DB, error)) {
+	backends[name] = ctor
+}
+
+// NewDB opens the database called name within dir using the backend
+// registered as backend. The meaning of name and dir (e.g. whether a file
+// extension is appended, whether dir is created) is backend-specific.
+func NewDB(name string, backend BackendType, dir string) (DB, error) {
+	ctor, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("gdbm: unknown backend %q", backend)
+	}
+	return ctor(name, dir)
+}
@@ -0,0 +1,86 @@
+package gdbm
+
+import "path/filepath"
+
+func init() {
+	RegisterBackend(GDBMBackend, openGDBMBackend)
+}
+
+// gdbmDB adapts *Database to the DB interface. The underlying Database is
+// still reachable through its own Fetch/Store/Exists API; gdbmDB just gives
+// it the names and error signatures DB callers expect.
+type gdbmDB struct {
+	*Database
+}
+
+func openGDBMBackend(name, dir string) (DB, error) {
+	db, err := Open(filepath.Join(dir, name+".gdbm"))
+	if err != nil {
+		return nil, err
+	}
+	return &gdbmDB{db}, nil
+}
+
+func (d *gdbmDB) Get(key []byte) ([]byte, error) {
+	return d.Fetch(key), nil
+}
+
+func (d *gdbmDB) Has(key []byte) (bool, error) {
+	return d.Exists(key), nil
+}
+
+func (d *gdbmDB) Set(key, value []byte) error {
+	if value == nil {
+		return errNilValue
+	}
+	return d.storeChecked(key, value)
+}
+
+func (d *gdbmDB) Delete(key []byte) error {
+	return d.storeChecked(key, nil)
+}
+
+func (d *gdbmDB) Close() error {
+	d.Database.Close()
+	return nil
+}
+
+func (d *gdbmDB) Iterator() (Iterator, error) {
+	var keys [][]byte
+	d.Database.Iterate(func(key []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return &gdbmIterator{db: d.Database, keys: keys, idx: -1}, nil
+}
+
+func (d *gdbmDB) NewBatch() Batch {
+	return d.Database.NewBatch()
+}
+
+// gdbmIterator walks a snapshot of the keys present in the database at the
+// time Iterator was called. GDBM's iteration API is callback-based, so the
+// keys are gathered up front to provide the pull-based Iterator interface;
+// values are fetched lazily as the iterator is advanced.
+type gdbmIterator struct {
+	db   *Database
+	keys [][]byte
+	idx  int
+}
+
+func (it *gdbmIterator) Next() bool {
+	it.idx++
+	return it.idx < len(it.keys)
+}
+
+func (it *gdbmIterator) Key() []byte {
+	return it.keys[it.idx]
+}
+
+func (it *gdbmIterator) Value() []byte {
+	return it.db.Fetch(it.keys[it.idx])
+}
+
+func (it *gdbmIterator) Close() error {
+	return nil
+}
@@ -0,0 +1,156 @@
+package gdbm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func withDB(t *testing.T, backend BackendType, f func(DB)) {
+	dir, err := ioutil.TempDir("", "go-gdbm-db-test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewDB("test", backend, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	f(db)
+}
+
+func forEachBackend(t *testing.T, f func(*testing.T, DB)) {
+	for _, backend := range []BackendType{GDBMBackend, MemDBBackend} {
+		backend := backend
+		t.Run(string(backend), func(t *testing.T) {
+			withDB(t, backend, func(db DB) {
+				f(t, db)
+			})
+		})
+	}
+}
+
+func TestDBGetSet(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		key, value := []byte("key"), []byte("value")
+		if got, err := db.Get(key); err != nil || got != nil {
+			t.Errorf("expected nil, nil but got %v, %v", got, err)
+		}
+		if err := db.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+		got, err := db.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("expected %v but got %v", value, got)
+		}
+	})
+}
+
+func TestDBSetNilValue(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		if err := db.Set([]byte("key"), nil); !errors.Is(err, errNilValue) {
+			t.Errorf("expected errNilValue but got %v", err)
+		}
+	})
+}
+
+func TestDBHasAndDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		key := []byte("key")
+		if ok, err := db.Has(key); err != nil || ok {
+			t.Errorf("expected false, nil but got %v, %v", ok, err)
+		}
+		if err := db.Set(key, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := db.Has(key); err != nil || !ok {
+			t.Errorf("expected true, nil but got %v, %v", ok, err)
+		}
+		if err := db.Delete(key); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := db.Has(key); err != nil || ok {
+			t.Errorf("expected false, nil but got %v, %v", ok, err)
+		}
+	})
+}
+
+func TestDBIterator(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		want := map[string]string{"a": "1", "b": "2", "c": "3"}
+		for k, v := range want {
+			if err := db.Set([]byte(k), []byte(v)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		it, err := db.Iterator()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer it.Close()
+
+		got := map[string]string{}
+		for it.Next() {
+			got[string(it.Key())] = string(it.Value())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+}
+
+func TestDBBatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		batch := db.NewBatch()
+		defer batch.Close()
+
+		batch.Set([]byte("a"), []byte("1"))
+		batch.Set([]byte("b"), []byte("2"))
+		batch.Delete([]byte("b"))
+
+		if got, _ := db.Get([]byte("a")); got != nil {
+			t.Error("batch write should not be visible before Write")
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, _ := db.Get([]byte("a")); !reflect.DeepEqual(got, []byte("1")) {
+			t.Errorf("expected %v but got %v", []byte("1"), got)
+		}
+		if got, _ := db.Get([]byte("b")); got != nil {
+			t.Errorf("expected nil but got %v", got)
+		}
+	})
+}
+
+func TestDBBatchOverwritesExistingKey(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db DB) {
+		if err := db.Set([]byte("a"), []byte("old")); err != nil {
+			t.Fatal(err)
+		}
+
+		batch := db.NewBatch()
+		defer batch.Close()
+		batch.Set([]byte("a"), []byte("new"))
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := db.Get([]byte("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, []byte("new")) {
+			t.Errorf("expected %v but got %v", []byte("new"), got)
+		}
+	})
+}
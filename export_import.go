@@ -0,0 +1,180 @@
+package gdbm
+
+import (
+	"archive/tar"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+// tarManifestName is the name of the leading entry ExportTar writes,
+// recording the total number of records in the stream so ImportTar can
+// sanity-check that it saw them all.
+const tarManifestName = "gdbm-manifest"
+
+// tarCRC32Header is the PAX extended header key ExportTar stores each
+// record's value checksum under.
+const tarCRC32Header = "GDBM.crc32"
+
+// ExportTar writes every key/value pair in d to w as a tar stream: a
+// manifest entry giving the total record count, followed by one entry per
+// record. The key is used as the entry name, with bytes outside printable
+// ASCII (and '\\'/'/' themselves) hex-escaped as "\xHH" so tar path rules
+// can't corrupt it, while keeping typical text keys readable in the
+// resulting archive. Each entry's CRC32 checksum of its value is stored in
+// a PAX extended header and verified by ImportTar.
+//
+// The format is independent of gdbm's on-disk file version, so it can be
+// used to move data between libgdbm versions that are otherwise
+// incompatible. The manifest's record count must match a single,
+// consistent view of the database, so ExportTar first collects all keys
+// via one Iterate pass (the only part held in memory); values are then
+// fetched and streamed to w one at a time from that fixed key list, rather
+// than materializing the whole database.
+func (d *Database) ExportTar(w io.Writer) error {
+	var keys [][]byte
+	d.Iterate(func(key []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	tw := tar.NewWriter(w)
+	manifest := strconv.Itoa(len(keys))
+	if err := tw.WriteHeader(&tar.Header{
+		Name: tarManifestName,
+		Mode: 0644,
+		Size: int64(len(manifest)),
+	}); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(tw, manifest); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		value := d.Fetch(key)
+		sum := crc32.ChecksumIEEE(value)
+		hdr := &tar.Header{
+			Name: escapeTarKey(key),
+			Mode: 0644,
+			Size: int64(len(value)),
+			PAXRecords: map[string]string{
+				tarCRC32Header: strconv.FormatUint(uint64(sum), 16),
+			},
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(value); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// ImportTar reads a tar stream produced by ExportTar and replays its
+// records into d via a single Batch, committed with WriteSync once the
+// whole stream has been read and verified.
+func (d *Database) ImportTar(r io.Reader) error {
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return fmt.Errorf("gdbm: reading tar manifest: %w", err)
+	}
+	if hdr.Name != tarManifestName {
+		return fmt.Errorf("gdbm: expected manifest entry %q, got %q", tarManifestName, hdr.Name)
+	}
+	manifest, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return fmt.Errorf("gdbm: reading tar manifest: %w", err)
+	}
+	want, err := strconv.Atoi(string(manifest))
+	if err != nil {
+		return fmt.Errorf("gdbm: invalid manifest record count %q: %w", manifest, err)
+	}
+
+	batch := d.NewBatch()
+	defer batch.Close()
+
+	var got int
+	for {
+		hdr, err = tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		key, err := unescapeTarKey(hdr.Name)
+		if err != nil {
+			return fmt.Errorf("gdbm: decoding key from tar entry %q: %w", hdr.Name, err)
+		}
+		value, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if sum, ok := hdr.PAXRecords[tarCRC32Header]; ok {
+			want, err := strconv.ParseUint(sum, 16, 32)
+			if err != nil || uint32(want) != crc32.ChecksumIEEE(value) {
+				return fmt.Errorf("gdbm: checksum mismatch for key %q", hdr.Name)
+			}
+		}
+
+		batch.Set(key, value)
+		got++
+	}
+	if got != want {
+		return fmt.Errorf("gdbm: manifest declared %d records but tar contained %d", want, got)
+	}
+
+	return batch.WriteSync()
+}
+
+// escapeTarKey encodes key as a tar entry name. Printable ASCII is kept
+// as-is (so text keys remain readable and diffable in the resulting
+// archive); every other byte, along with '\\' and '/', is hex-escaped as
+// "\xHH". An empty key is represented as "\0", since tar entry names can't
+// be empty.
+func escapeTarKey(key []byte) string {
+	if len(key) == 0 {
+		return `\0`
+	}
+	out := make([]byte, 0, len(key))
+	for _, b := range key {
+		if b >= 0x20 && b < 0x7f && b != '\\' && b != '/' {
+			out = append(out, b)
+		} else {
+			out = append(out, []byte(fmt.Sprintf(`\x%02x`, b))...)
+		}
+	}
+	return string(out)
+}
+
+// unescapeTarKey reverses escapeTarKey.
+func unescapeTarKey(name string) ([]byte, error) {
+	if name == `\0` {
+		return []byte{}, nil
+	}
+	key := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		if name[i] != '\\' {
+			key = append(key, name[i])
+			continue
+		}
+		if i+3 >= len(name) || name[i+1] != 'x' {
+			return nil, fmt.Errorf("gdbm: invalid escape in %q", name)
+		}
+		b, err := strconv.ParseUint(name[i+2:i+4], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("gdbm: invalid escape in %q: %w", name, err)
+		}
+		key = append(key, byte(b))
+		i += 3
+	}
+	return key, nil
+}
@@ -0,0 +1,77 @@
+package gdbm
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEscapeTarKeyRoundTrip(t *testing.T) {
+	keys := [][]byte{
+		[]byte("hello"),
+		[]byte{0, 1, 2, 255},
+		{},
+		[]byte("a/b\\c"),
+	}
+	for _, key := range keys {
+		name := escapeTarKey(key)
+		got, err := unescapeTarKey(name)
+		if err != nil {
+			t.Fatalf("unescapeTarKey(%q): %v", name, err)
+		}
+		if !reflect.DeepEqual(got, key) {
+			t.Errorf("round-trip of %v through %q produced %v", key, name, got)
+		}
+	}
+}
+
+func TestExportImportTar(t *testing.T) {
+	mockDb(func(src *Database) {
+		want := map[string]string{
+			"a":        "1",
+			"b":        "2",
+			"c":        "",
+			"":         "empty-key",
+			"\x00\x01": "binary",
+		}
+		for k, v := range want {
+			src.Store([]byte(k), []byte(v))
+		}
+
+		var buf bytes.Buffer
+		if err := src.ExportTar(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		mockDb(func(dst *Database) {
+			if err := dst.ImportTar(&buf); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range want {
+				got := dst.Fetch([]byte(k))
+				if !reflect.DeepEqual(got, []byte(v)) {
+					t.Errorf("key %q: expected %q but got %q", k, v, got)
+				}
+			}
+		})
+	})
+}
+
+func TestImportTarCorruptChecksum(t *testing.T) {
+	mockDb(func(src *Database) {
+		src.Store([]byte("key"), []byte("value"))
+
+		var buf bytes.Buffer
+		if err := src.ExportTar(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		corrupted := bytes.Replace(buf.Bytes(), []byte("value"), []byte("VALUE"), 1)
+
+		mockDb(func(dst *Database) {
+			if err := dst.ImportTar(bytes.NewReader(corrupted)); err == nil {
+				t.Error("expected checksum mismatch error, got nil")
+			}
+		})
+	})
+}
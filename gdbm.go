@@ -9,6 +9,8 @@ package gdbm
 import "C"
 
 import (
+	"fmt"
+	"sync"
 	"unsafe"
 )
 
@@ -27,6 +29,13 @@ const (
 
 type Database struct {
 	dbf C.GDBM_FILE
+
+	// mu guards dbf, since GDBM's C library is not reentrant on a single
+	// handle - including for reads, which still mutate its internal bucket
+	// cache. skipLock disables it for callers who synchronize themselves
+	// and want to avoid the overhead.
+	mu       sync.Mutex
+	skipLock bool
 }
 
 type Config struct {
@@ -47,6 +56,11 @@ type Config struct {
 	// CacheSize sets the size of the internal bucket cache. If zero, the cache size
 	// will be set to 100.
 	CacheSize int
+	// If true, Database will not synchronize concurrent access to the
+	// handle with an internal mutex. Only set this if the caller already
+	// ensures the Database is never used from more than one goroutine at
+	// a time; doing otherwise will corrupt the database.
+	Unsafe bool
 }
 
 // Opens a database using the given configuration options.
@@ -66,7 +80,7 @@ func OpenConfig(config *Config) (*Database, error) {
 			val := C.int(config.CacheSize)
 			C.gdbm_setopt(dbf, C.GDBM_CACHESIZE, &val, C.int(unsafe.Sizeof(val)))
 		}
-		return &Database{dbf}, nil
+		return &Database{dbf: dbf, skipLock: config.Unsafe}, nil
 	}
 	return nil, err
 }
@@ -83,12 +97,16 @@ func Open(file string) (*Database, error) {
 
 // Closes the database and releases all associated resources.
 func (d *Database) Close() {
+	d.lock()
+	defer d.unlock()
 	C.gdbm_close(d.dbf)
 }
 
 // Returns the data associated with a given key, or nil if the key is not
 // present in the database.
 func (d *Database) Fetch(key []byte) (value []byte) {
+	d.rlock()
+	defer d.runlock()
 	dkey := toDatum(key)
 	dval := C.gdbm_fetch(d.dbf, dkey)
 	if dval.dptr != nil {
@@ -100,17 +118,48 @@ func (d *Database) Fetch(key []byte) (value []byte) {
 
 // Stores data for a specified key. Any existing data for the key will be replaced.
 // If `data` is nil, then the key will be deleted if it exists in the database.
+//
+// Store does not report a failed gdbm_store/gdbm_delete (e.g. the database
+// was opened read-only, or the disk is full); callers that need to know
+// about that should use the error-returning DB interface instead, whose
+// gdbm backend goes through storeLocked.
 func (d *Database) Store(key, data []byte) {
+	d.lock()
+	defer d.unlock()
+	d.storeLocked(key, data)
+}
+
+// storeChecked is like Store, but reports a failed gdbm_store/gdbm_delete
+// instead of discarding it. It exists for DB implementations that need
+// Store's error, such as gdbmDB.
+func (d *Database) storeChecked(key, data []byte) error {
+	d.lock()
+	defer d.unlock()
+	return d.storeLocked(key, data)
+}
+
+// storeLocked does the actual gdbm_store/gdbm_delete call and checks its
+// return code, assuming the caller already holds d's lock. batch.apply also
+// uses this so a batch's buffered writes get the same error handling as a
+// single Store/Delete, under one lock acquisition for the whole batch.
+func (d *Database) storeLocked(key, data []byte) error {
 	dkey := toDatum(key)
 	if data == nil {
-		C.gdbm_delete(d.dbf, dkey)
-	} else {
-		C.gdbm_store(d.dbf, dkey, toDatum(data), C.GDBM_REPLACE)
+		if ret := C.gdbm_delete(d.dbf, dkey); ret != 0 && C.gdbm_errno != C.GDBM_ITEM_NOT_FOUND {
+			return fmt.Errorf("gdbm: delete of %q failed: %s", key, C.GoString(C.gdbm_strerror(C.gdbm_errno)))
+		}
+		return nil
 	}
+	if ret := C.gdbm_store(d.dbf, dkey, toDatum(data), C.GDBM_REPLACE); ret != 0 {
+		return fmt.Errorf("gdbm: store of %q failed: %s", key, C.GoString(C.gdbm_strerror(C.gdbm_errno)))
+	}
+	return nil
 }
 
 // Returns true if the specified key is found in the database.
 func (d *Database) Exists(key []byte) bool {
+	d.rlock()
+	defer d.runlock()
 	ret := C.gdbm_exists(d.dbf, toDatum(key))
 	return ret != 0
 }
@@ -118,18 +167,34 @@ func (d *Database) Exists(key []byte) bool {
 // Iterates through all the keys in the database. The callback will be called for
 // each key. The callback should return true unless it wants to cancel the iteration.
 // Keys will be traversed in an unspecified order.
+//
+// The callback is free to call other Database methods, such as Fetch: the
+// lock guarding dbf is only held around the individual gdbm_firstkey/
+// gdbm_nextkey calls, not for the duration of the callback.
 func (d *Database) Iterate(callback func(key []byte) (cont bool)) {
-	key := C.gdbm_firstkey(d.dbf)
+	key := d.firstkey()
 	for key.dptr != nil {
 		key = d.iterKey(callback, key)
 	}
 }
 
+func (d *Database) firstkey() C.datum {
+	d.lock()
+	defer d.unlock()
+	return C.gdbm_firstkey(d.dbf)
+}
+
+func (d *Database) nextkey(key C.datum) C.datum {
+	d.lock()
+	defer d.unlock()
+	return C.gdbm_nextkey(d.dbf, key)
+}
+
 func (d *Database) iterKey(callback func([]byte) bool, key C.datum) (next C.datum) {
 	defer C.free(unsafe.Pointer(key.dptr))
 	bytes := C.GoBytes(unsafe.Pointer(key.dptr), key.dsize)
 	if callback(bytes) {
-		next = C.gdbm_nextkey(d.dbf, key)
+		next = d.nextkey(key)
 	}
 	return
 }
@@ -138,15 +203,44 @@ func (d *Database) iterKey(callback func([]byte) bool, key C.datum) (next C.datu
 // This should be used very infrequently, and will only be useful after a lot of deletions
 // have been made.
 func (d *Database) Reorganize() {
+	d.lock()
+	defer d.unlock()
 	C.gdbm_reorganize(d.dbf)
 }
 
 // Synchronizes the database to disk. Sync will only return once the database has been
 // physically written to the disk.
 func (d *Database) Sync() {
+	d.lock()
+	defer d.unlock()
 	C.gdbm_sync(d.dbf)
 }
 
+func (d *Database) lock() {
+	if !d.skipLock {
+		d.mu.Lock()
+	}
+}
+
+func (d *Database) unlock() {
+	if !d.skipLock {
+		d.mu.Unlock()
+	}
+}
+
+// rlock/runlock exist so read-only methods read as read-only at the call
+// site, but every C.gdbm_* call - including fetch/exists - touches dbf's
+// per-handle bucket cache, not just writes. GDBM is not reentrant on a
+// single handle for reads either, so these must take the same exclusive
+// lock as lock/unlock rather than an actual RWMutex read-lock.
+func (d *Database) rlock() {
+	d.lock()
+}
+
+func (d *Database) runlock() {
+	d.unlock()
+}
+
 func toDatum(data []byte) C.datum {
 	var ptr unsafe.Pointer
 	var size int
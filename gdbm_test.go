@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -161,6 +162,122 @@ func TestIterateCancel(t *testing.T) {
 	})
 }
 
+func TestBatch(t *testing.T) {
+	mockDb(func(d *Database) {
+		d.Store([]byte("stale"), []byte("old"))
+
+		batch := d.NewBatch()
+		batch.Set([]byte("a"), []byte("1"))
+		batch.Set([]byte("b"), []byte("2"))
+		batch.Delete([]byte("stale"))
+
+		if v := d.Fetch([]byte("a")); v != nil {
+			t.Errorf("expected batch writes to be invisible before Write, got %v", v)
+		}
+
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+		batch.Close()
+
+		if v := d.Fetch([]byte("a")); !reflect.DeepEqual(v, []byte("1")) {
+			t.Errorf("expected %v but got %v", []byte("1"), v)
+		}
+		if v := d.Fetch([]byte("b")); !reflect.DeepEqual(v, []byte("2")) {
+			t.Errorf("expected %v but got %v", []byte("2"), v)
+		}
+		if v := d.Fetch([]byte("stale")); v != nil {
+			t.Errorf("expected stale key to be deleted, got %v", v)
+		}
+	})
+}
+
+func TestBatchOverwritesExistingKey(t *testing.T) {
+	mockDb(func(d *Database) {
+		d.Store([]byte("a"), []byte("old"))
+
+		batch := d.NewBatch()
+		batch.Set([]byte("a"), []byte("new"))
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+		batch.Close()
+
+		if v := d.Fetch([]byte("a")); !reflect.DeepEqual(v, []byte("new")) {
+			t.Errorf("expected %v but got %v", []byte("new"), v)
+		}
+	})
+}
+
+func TestBatchWriteSync(t *testing.T) {
+	mockDb(func(d *Database) {
+		batch := d.NewBatch()
+		batch.Set([]byte("k"), []byte("v"))
+		if err := batch.WriteSync(); err != nil {
+			t.Fatal(err)
+		}
+		if v := d.Fetch([]byte("k")); !reflect.DeepEqual(v, []byte("v")) {
+			t.Errorf("expected %v but got %v", []byte("v"), v)
+		}
+	})
+}
+
+// Test_Database_threadSafety hammers a single Database from many goroutines
+// at once. It exercises the Go-level locking (-race will flag a missing or
+// too-narrow lock around the shared *Database state), but it cannot
+// observe corruption of libgdbm's own internal bucket cache, which lives
+// entirely in C memory outside the Go race detector's view - that
+// guarantee instead rests on every C.gdbm_* call going through the same
+// mutex.
+func Test_Database_threadSafety(t *testing.T) {
+	mockDb(func(d *Database) {
+		const n = 1000
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func(i int) {
+				defer wg.Done()
+				key := []byte(strconv.Itoa(i % 100))
+				switch i % 3 {
+				case 0:
+					d.Store(key, []byte("value"))
+				case 1:
+					d.Fetch(key)
+				case 2:
+					d.Exists(key)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestDatabaseUnsafeMode(t *testing.T) {
+	tmp, err := ioutil.TempFile("", "go-gdbm-test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	d, err := OpenConfig(&Config{
+		File:   tmp.Name(),
+		Perm:   0666,
+		Mode:   Create,
+		Unsafe: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	key, value := []byte("key"), []byte("value")
+	d.Store(key, value)
+	if v := d.Fetch(key); !reflect.DeepEqual(v, value) {
+		t.Errorf("expected %v but got %v", value, v)
+	}
+}
+
 func populate(d *Database, n int) {
 	for i := 0; i < n; i++ {
 		key := []byte(strconv.Itoa(i))
@@ -0,0 +1,57 @@
+package gdbm
+
+import "bytes"
+
+// IterateFrom iterates over all key/value pairs in the database, fetching
+// the value alongside each key so callers don't need a separate Fetch per
+// key the way Iterate requires. Iteration begins at the bucket containing
+// start and wraps around, stopping just before start would be revisited.
+//
+// GDBM is an unordered hash table, so "starting at start" says nothing
+// about key ordering - it only means a full pass still visits every key
+// exactly once, but the pass begins wherever start happens to fall rather
+// than at GDBM's internal first key. IterateFrom(lastKey, ...) therefore
+// visits lastKey itself first, then the keys that follow it, and then wraps
+// around to the keys that precede it, so keys seen before lastKey in an
+// earlier pass are visited again at the end of this one unless cb returns
+// false first. If start is not present in the database, iteration begins at the
+// beginning, since GDBM exposes no way to locate the bucket for a
+// nonexistent key.
+func (d *Database) IterateFrom(start []byte, cb func(key, value []byte) bool) {
+	var keys [][]byte
+	d.Iterate(func(key []byte) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) == 0 {
+		return
+	}
+
+	startIdx := 0
+	for i, key := range keys {
+		if bytes.Equal(key, start) {
+			startIdx = i
+			break
+		}
+	}
+
+	for i := 0; i < len(keys); i++ {
+		key := keys[(startIdx+i)%len(keys)]
+		if !cb(key, d.Fetch(key)) {
+			return
+		}
+	}
+}
+
+// IterateRange iterates over the key/value pairs for which filter returns
+// true, fetching a key's value only once filter has accepted it so keys
+// filtered out cost nothing beyond the key itself. As with Iterate, cb
+// should return true to continue or false to cancel the iteration.
+func (d *Database) IterateRange(filter func(key []byte) bool, cb func(key, value []byte) bool) {
+	d.Iterate(func(key []byte) bool {
+		if !filter(key) {
+			return true
+		}
+		return cb(key, d.Fetch(key))
+	})
+}
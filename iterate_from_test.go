@@ -0,0 +1,124 @@
+package gdbm
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIterateFromVisitsEveryKeyOnce(t *testing.T) {
+	mockDb(func(d *Database) {
+		want := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}
+		for k, v := range want {
+			d.Store([]byte(k), []byte(v))
+		}
+
+		got := map[string]string{}
+		d.IterateFrom([]byte("c"), func(key, value []byte) bool {
+			if _, dup := got[string(key)]; dup {
+				t.Errorf("key %q visited twice", key)
+			}
+			got[string(key)] = string(value)
+			return true
+		})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+}
+
+func TestIterateFromMissingStart(t *testing.T) {
+	mockDb(func(d *Database) {
+		want := map[string]string{"a": "1", "b": "2"}
+		for k, v := range want {
+			d.Store([]byte(k), []byte(v))
+		}
+
+		got := map[string]string{}
+		d.IterateFrom([]byte("does-not-exist"), func(key, value []byte) bool {
+			got[string(key)] = string(value)
+			return true
+		})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+}
+
+func TestIterateFromEmptyDatabase(t *testing.T) {
+	mockDb(func(d *Database) {
+		called := false
+		d.IterateFrom([]byte("x"), func(key, value []byte) bool {
+			called = true
+			return true
+		})
+		if called {
+			t.Error("callback should not be invoked on an empty database")
+		}
+	})
+}
+
+func TestIterateFromCancel(t *testing.T) {
+	mockDb(func(d *Database) {
+		for i := 0; i < 10; i++ {
+			d.Store([]byte{byte(i)}, []byte{byte(i)})
+		}
+
+		n := 0
+		d.IterateFrom([]byte{0}, func(key, value []byte) bool {
+			n++
+			return n < 3
+		})
+		if n != 3 {
+			t.Errorf("expected cancellation after 3 keys, got %d", n)
+		}
+	})
+}
+
+func TestIterateRangeFiltersAndFetches(t *testing.T) {
+	mockDb(func(d *Database) {
+		all := map[string]string{"a1": "1", "a2": "2", "b1": "3", "b2": "4"}
+		for k, v := range all {
+			d.Store([]byte(k), []byte(v))
+		}
+
+		var got []string
+		d.IterateRange(
+			func(key []byte) bool { return key[0] == 'a' },
+			func(key, value []byte) bool {
+				if string(value) != all[string(key)] {
+					t.Errorf("value for %q: expected %q but got %q", key, all[string(key)], value)
+				}
+				got = append(got, string(key))
+				return true
+			},
+		)
+
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, []string{"a1", "a2"}) {
+			t.Errorf("expected [a1 a2] but got %v", got)
+		}
+	})
+}
+
+func TestIterateRangeCancel(t *testing.T) {
+	mockDb(func(d *Database) {
+		for i := 0; i < 10; i++ {
+			d.Store([]byte{byte(i)}, []byte{byte(i)})
+		}
+
+		n := 0
+		d.IterateRange(
+			func(key []byte) bool { return true },
+			func(key, value []byte) bool {
+				n++
+				return n < 2
+			},
+		)
+		if n != 2 {
+			t.Errorf("expected cancellation after 2 keys, got %d", n)
+		}
+	})
+}
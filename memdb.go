@@ -0,0 +1,141 @@
+package gdbm
+
+import (
+	"sort"
+	"sync"
+)
+
+func init() {
+	RegisterBackend(MemDBBackend, openMemDB)
+}
+
+// memDB is a trivial in-memory DB implementation. It exists mainly as a
+// lightweight reference backend and as a replacement for the temp-file-backed
+// databases tests previously had to create just to exercise the DB
+// interface.
+type memDB struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+func openMemDB(name, dir string) (DB, error) {
+	return &memDB{data: make(map[string][]byte)}, nil
+}
+
+func (m *memDB) Get(key []byte) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.data[string(key)]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte{}, value...), nil
+}
+
+func (m *memDB) Has(key []byte) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[string(key)]
+	return ok, nil
+}
+
+func (m *memDB) Set(key, value []byte) error {
+	if value == nil {
+		return errNilValue
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *memDB) Delete(key []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, string(key))
+	return nil
+}
+
+func (m *memDB) Close() error {
+	return nil
+}
+
+func (m *memDB) Iterator() (Iterator, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]string, 0, len(m.data))
+	for key := range m.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return &memIterator{db: m, keys: keys, idx: -1}, nil
+}
+
+func (m *memDB) NewBatch() Batch {
+	return &memBatch{db: m}
+}
+
+type memIterator struct {
+	db   *memDB
+	keys []string
+	idx  int
+}
+
+func (it *memIterator) Next() bool {
+	it.idx++
+	return it.idx < len(it.keys)
+}
+
+func (it *memIterator) Key() []byte {
+	return []byte(it.keys[it.idx])
+}
+
+func (it *memIterator) Value() []byte {
+	it.db.mu.RLock()
+	defer it.db.mu.RUnlock()
+	return append([]byte(nil), it.db.data[it.keys[it.idx]]...)
+}
+
+func (it *memIterator) Close() error {
+	return nil
+}
+
+type memOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+type memBatch struct {
+	db  *memDB
+	ops []memOp
+}
+
+func (b *memBatch) Set(key, value []byte) {
+	b.ops = append(b.ops, memOp{key: key, value: value})
+}
+
+func (b *memBatch) Delete(key []byte) {
+	b.ops = append(b.ops, memOp{key: key, delete: true})
+}
+
+func (b *memBatch) Write() error {
+	b.db.mu.Lock()
+	defer b.db.mu.Unlock()
+	for _, op := range b.ops {
+		if op.delete {
+			delete(b.db.data, string(op.key))
+		} else {
+			b.db.data[string(op.key)] = append([]byte(nil), op.value...)
+		}
+	}
+	return nil
+}
+
+func (b *memBatch) WriteSync() error {
+	return b.Write()
+}
+
+func (b *memBatch) Close() {
+	b.ops = nil
+}
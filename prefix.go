@@ -0,0 +1,108 @@
+package gdbm
+
+import "bytes"
+
+// PrefixDB wraps a Database, transparently prepending prefix to every key.
+// This lets a single gdbm file back multiple independent logical stores
+// (e.g. per-module data) without their keys colliding.
+//
+// The raw key is simply prefix followed by key, so namespaces only stay
+// apart when no prefix is a prefix of another's. With "a" and "ab", key
+// "bx" under "a" and key "x" under "ab" are the same raw key "abx", so
+// Fetch/Store/Exists/Delete on one reach the other's data. Iterate only
+// checks that a raw key begins with prefix, so Iterate on the shorter
+// prefix also sees keys stored under the longer one. Callers sharing a
+// Database between multiple
+// PrefixDBs must choose prefixes such that none is a prefix of another -
+// e.g. by giving every prefix a common fixed length, or by ending each one
+// in a separator byte that never otherwise appears at that position (such
+// as "a/" and "ab/").
+type PrefixDB struct {
+	db     *Database
+	prefix []byte
+}
+
+// NewPrefixDB returns a PrefixDB that scopes all operations on db to keys
+// beginning with prefix. See the PrefixDB doc comment for the constraint
+// this places on prefix when a Database is shared by more than one
+// PrefixDB.
+func NewPrefixDB(db *Database, prefix []byte) *PrefixDB {
+	return &PrefixDB{db: db, prefix: prefix}
+}
+
+// Fetch returns the data associated with key within the prefixed namespace,
+// or nil if it is not present.
+func (p *PrefixDB) Fetch(key []byte) []byte {
+	return p.db.Fetch(prefixKey(p.prefix, key))
+}
+
+// Store stores data for key within the prefixed namespace. If data is nil,
+// the key is deleted.
+func (p *PrefixDB) Store(key, data []byte) {
+	p.db.Store(prefixKey(p.prefix, key), data)
+}
+
+// Exists reports whether key is present within the prefixed namespace.
+func (p *PrefixDB) Exists(key []byte) bool {
+	return p.db.Exists(prefixKey(p.prefix, key))
+}
+
+// Delete removes key from the prefixed namespace. It is equivalent to
+// Store(key, nil).
+func (p *PrefixDB) Delete(key []byte) {
+	p.db.Store(prefixKey(p.prefix, key), nil)
+}
+
+// Iterate iterates through the keys within the prefixed namespace, with the
+// prefix stripped before callback is invoked. As with Database.Iterate,
+// callback should return true to continue iteration or false to cancel it.
+//
+// Iterate identifies "within the prefixed namespace" purely by byte prefix,
+// so it leaks keys from another PrefixDB whose prefix is itself a prefix of
+// this one's - see the PrefixDB doc comment.
+func (p *PrefixDB) Iterate(callback func(key []byte) (cont bool)) {
+	p.db.Iterate(func(key []byte) bool {
+		if !bytes.HasPrefix(key, p.prefix) {
+			return true
+		}
+		return callback(key[len(p.prefix):])
+	})
+}
+
+// NewBatch returns a Batch that prefixes every key before forwarding the
+// operation to a batch on the underlying Database.
+func (p *PrefixDB) NewBatch() Batch {
+	return &prefixBatch{batch: p.db.NewBatch(), prefix: p.prefix}
+}
+
+func prefixKey(prefix, key []byte) []byte {
+	pkey := make([]byte, 0, len(prefix)+len(key))
+	pkey = append(pkey, prefix...)
+	pkey = append(pkey, key...)
+	return pkey
+}
+
+type prefixBatch struct {
+	batch  Batch
+	prefix []byte
+}
+
+func (b *prefixBatch) Set(key, value []byte) {
+	b.batch.Set(prefixKey(b.prefix, key), value)
+}
+
+func (b *prefixBatch) Delete(key []byte) {
+	b.batch.Delete(prefixKey(b.prefix, key))
+}
+
+func (b *prefixBatch) Write() error {
+	return b.batch.Write()
+}
+
+func (b *prefixBatch) WriteSync() error {
+	return b.batch.WriteSync()
+}
+
+func (b *prefixBatch) Close() {
+	b.batch.Close()
+}
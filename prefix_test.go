@@ -0,0 +1,167 @@
+package gdbm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefixDBStoreFetch(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns1/"))
+		q := NewPrefixDB(d, []byte("ns2/"))
+
+		p.Store([]byte("key"), []byte("from-p"))
+		q.Store([]byte("key"), []byte("from-q"))
+
+		if v := p.Fetch([]byte("key")); !reflect.DeepEqual(v, []byte("from-p")) {
+			t.Errorf("expected %v but got %v", []byte("from-p"), v)
+		}
+		if v := q.Fetch([]byte("key")); !reflect.DeepEqual(v, []byte("from-q")) {
+			t.Errorf("expected %v but got %v", []byte("from-q"), v)
+		}
+
+		// The underlying database should see the raw, prefixed keys.
+		if v := d.Fetch([]byte("ns1/key")); !reflect.DeepEqual(v, []byte("from-p")) {
+			t.Errorf("expected %v but got %v", []byte("from-p"), v)
+		}
+	})
+}
+
+func TestPrefixDBEmptyPrefix(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, nil)
+		p.Store([]byte("key"), []byte("value"))
+		if v := d.Fetch([]byte("key")); !reflect.DeepEqual(v, []byte("value")) {
+			t.Errorf("expected %v but got %v", []byte("value"), v)
+		}
+		if v := p.Fetch([]byte("key")); !reflect.DeepEqual(v, []byte("value")) {
+			t.Errorf("expected %v but got %v", []byte("value"), v)
+		}
+	})
+}
+
+func TestPrefixDBEmptyKey(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns/"))
+		p.Store([]byte{}, []byte("value"))
+		if v := p.Fetch([]byte{}); !reflect.DeepEqual(v, []byte("value")) {
+			t.Errorf("expected %v but got %v", []byte("value"), v)
+		}
+		if v := d.Fetch([]byte("ns/")); !reflect.DeepEqual(v, []byte("value")) {
+			t.Errorf("expected %v but got %v", []byte("value"), v)
+		}
+	})
+}
+
+func TestPrefixDBExistsAndDelete(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns/"))
+		p.Store([]byte("key"), []byte("value"))
+		if !p.Exists([]byte("key")) {
+			t.Error("expected key to exist")
+		}
+		p.Delete([]byte("key"))
+		if p.Exists([]byte("key")) {
+			t.Error("expected key to be deleted")
+		}
+		if v := p.Fetch([]byte("key")); v != nil {
+			t.Errorf("expected nil but got %v", v)
+		}
+	})
+}
+
+func TestPrefixDBIterate(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns/"))
+		q := NewPrefixDB(d, []byte("other/"))
+
+		keys := []string{"a", "b", "c"}
+		for _, key := range keys {
+			p.Store([]byte(key), []byte("v"))
+		}
+		q.Store([]byte("leak"), []byte("v"))
+
+		seen := make(map[string]bool)
+		p.Iterate(func(key []byte) bool {
+			seen[string(key)] = true
+			return true
+		})
+
+		if len(seen) != len(keys) {
+			t.Errorf("expected %d keys but got %d: %v", len(keys), len(seen), seen)
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				t.Errorf("key %q not handled by Iterate", key)
+			}
+		}
+		if seen["other/leak"] || seen["leak"] {
+			t.Error("Iterate leaked a key from another namespace")
+		}
+	})
+}
+
+// TestPrefixDBIterateOverlappingPrefixesLeak documents a real limitation of
+// Iterate: it only checks that a raw key begins with the PrefixDB's prefix,
+// so a prefix that is itself a prefix of another PrefixDB's prefix (here
+// "a" and "ab") observes keys from both namespaces. This is why the
+// PrefixDB doc comment requires callers sharing a Database to pick
+// prefixes where none is a prefix of another.
+func TestPrefixDBIterateOverlappingPrefixesLeak(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("a"))
+		q := NewPrefixDB(d, []byte("ab"))
+
+		p.Store([]byte("x"), []byte("p"))
+		q.Store([]byte("y"), []byte("q"))
+
+		seen := map[string]bool{}
+		p.Iterate(func(key []byte) bool {
+			seen[string(key)] = true
+			return true
+		})
+
+		if !seen["by"] {
+			t.Fatal("expected this test to demonstrate the documented cross-namespace leak, but it didn't reproduce")
+		}
+	})
+}
+
+func TestPrefixDBBatch(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns/"))
+
+		batch := p.NewBatch()
+		batch.Set([]byte("a"), []byte("1"))
+		batch.Delete([]byte("b"))
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+		batch.Close()
+
+		if v := p.Fetch([]byte("a")); !reflect.DeepEqual(v, []byte("1")) {
+			t.Errorf("expected %v but got %v", []byte("1"), v)
+		}
+		if v := d.Fetch([]byte("ns/a")); !reflect.DeepEqual(v, []byte("1")) {
+			t.Errorf("expected batch to write through the prefix, got %v", v)
+		}
+	})
+}
+
+func TestPrefixDBBatchOverwritesExistingKey(t *testing.T) {
+	mockDb(func(d *Database) {
+		p := NewPrefixDB(d, []byte("ns/"))
+		p.Store([]byte("a"), []byte("old"))
+
+		batch := p.NewBatch()
+		batch.Set([]byte("a"), []byte("new"))
+		if err := batch.Write(); err != nil {
+			t.Fatal(err)
+		}
+		batch.Close()
+
+		if v := p.Fetch([]byte("a")); !reflect.DeepEqual(v, []byte("new")) {
+			t.Errorf("expected %v but got %v", []byte("new"), v)
+		}
+	})
+}